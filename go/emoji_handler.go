@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+// reactionEmojiCatalogEnforced がtrueの場合のみ、カタログに無い emoji_name を
+// 400で拒否する。既存ワークロードはカタログに無いショートコードを大量に投稿して
+// いるため、デフォルトは無効にして後方互換を保つ。
+var reactionEmojiCatalogEnforced = flag.Bool("reaction-emoji-catalog-enforced", false, "reject emoji_name values that are not in the bundled catalog")
+
+var errUnknownEmoji = errors.New("emoji_name is not in the allowed catalog")
+
+// EmojiDefinition はバンドルされた絵文字カタログの1エントリ。
+// Aliases はクライアントが送ってくる可能性のある別名（Unicode絵文字そのものや
+// 他サービス由来のショートコード）を正規のShortcodeへ正規化するために使う。
+type EmojiDefinition struct {
+	Shortcode string   `json:"shortcode"`
+	Category  string   `json:"category"`
+	Aliases   []string `json:"-"`
+}
+
+type EmojiCategory struct {
+	Name   string            `json:"name"`
+	Emojis []EmojiDefinition `json:"emojis"`
+}
+
+// emojiCatalog はstartup時に構築される、カタログ表示(GET /api/emoji)に使う
+// 標準ショートコードの一覧。github.com/github/gemoji の命名に倣っているが、
+// 全Unicodeショートコードを網羅してはいない。そのため投稿時の強制は
+// reactionEmojiCatalogEnforced が有効な場合のみ行う。
+var emojiCatalog = []EmojiDefinition{
+	{Shortcode: "smile", Category: "smileys", Aliases: []string{"\U0001F604", ":)"}},
+	{Shortcode: "smiley", Category: "smileys", Aliases: []string{"\U0001F603"}},
+	{Shortcode: "laughing", Category: "smileys", Aliases: []string{"\U0001F606", "lol"}},
+	{Shortcode: "joy", Category: "smileys", Aliases: []string{"\U0001F602"}},
+	{Shortcode: "wink", Category: "smileys", Aliases: []string{"\U0001F609", ";)"}},
+	{Shortcode: "blush", Category: "smileys", Aliases: []string{"\U0001F60A"}},
+	{Shortcode: "heart_eyes", Category: "smileys", Aliases: []string{"\U0001F60D"}},
+	{Shortcode: "thinking", Category: "smileys", Aliases: []string{"\U0001F914"}},
+	{Shortcode: "sob", Category: "smileys", Aliases: []string{"\U0001F62D"}},
+	{Shortcode: "cry", Category: "smileys", Aliases: []string{"\U0001F622"}},
+	{Shortcode: "angry", Category: "smileys", Aliases: []string{"\U0001F620"}},
+	{Shortcode: "scream", Category: "smileys", Aliases: []string{"\U0001F631"}},
+	{Shortcode: "sunglasses", Category: "smileys", Aliases: []string{"\U0001F60E"}},
+	{Shortcode: "heart", Category: "smileys", Aliases: []string{"❤️", "<3"}},
+	{Shortcode: "broken_heart", Category: "smileys", Aliases: []string{"\U0001F494"}},
+	{Shortcode: "thumbsup", Category: "gestures", Aliases: []string{"\U0001F44D", "+1"}},
+	{Shortcode: "thumbsdown", Category: "gestures", Aliases: []string{"\U0001F44E", "-1"}},
+	{Shortcode: "clap", Category: "gestures", Aliases: []string{"\U0001F44F"}},
+	{Shortcode: "raised_hands", Category: "gestures", Aliases: []string{"\U0001F64C"}},
+	{Shortcode: "wave", Category: "gestures", Aliases: []string{"\U0001F44B"}},
+	{Shortcode: "pray", Category: "gestures", Aliases: []string{"\U0001F64F"}},
+	{Shortcode: "muscle", Category: "gestures", Aliases: []string{"\U0001F4AA"}},
+	{Shortcode: "eyes", Category: "gestures", Aliases: []string{"\U0001F440"}},
+	{Shortcode: "ok_hand", Category: "gestures", Aliases: []string{"\U0001F44C"}},
+	{Shortcode: "fire", Category: "objects", Aliases: []string{"\U0001F525"}},
+	{Shortcode: "tada", Category: "objects", Aliases: []string{"\U0001F389"}},
+	{Shortcode: "star", Category: "objects", Aliases: []string{"⭐"}},
+	{Shortcode: "sparkles", Category: "objects", Aliases: []string{"\U00002728"}},
+	{Shortcode: "100", Category: "objects", Aliases: []string{"\U0001F4AF"}},
+	{Shortcode: "trophy", Category: "objects", Aliases: []string{"\U0001F3C6"}},
+	{Shortcode: "rocket", Category: "objects", Aliases: []string{"\U0001F680"}},
+	{Shortcode: "tv", Category: "objects", Aliases: []string{"\U0001F4FA"}},
+	{Shortcode: "microphone", Category: "objects", Aliases: []string{"\U0001F3A4"}},
+	{Shortcode: "dog", Category: "animals", Aliases: []string{"\U0001F436"}},
+	{Shortcode: "cat", Category: "animals", Aliases: []string{"\U0001F431"}},
+}
+
+// emojiAliasToShortcode は正規化用の逆引きテーブル。キーは shortcode とその別名すべて。
+var emojiAliasToShortcode map[string]string
+
+func init() {
+	emojiAliasToShortcode = make(map[string]string)
+	for _, def := range emojiCatalog {
+		emojiAliasToShortcode[def.Shortcode] = def.Shortcode
+		for _, alias := range def.Aliases {
+			emojiAliasToShortcode[alias] = def.Shortcode
+		}
+	}
+}
+
+// resolveEmojiName はクライアントから送られてきた emoji_name を検証する。
+// reactionEmojiCatalogEnforced が無効(デフォルト)の間は、投稿された値を
+// 一切書き換えずにそのまま返す。正規化してしまうと「送った値と保存された値が
+// 食い違う」という形で既存ワークロードを壊すため。カタログ強制を有効にした
+// 場合のみ、エイリアスを正規のshortcodeへ正規化し、カタログに無い名前は
+// errUnknownEmoji で拒否する。
+func resolveEmojiName(name string) (string, error) {
+	if !*reactionEmojiCatalogEnforced {
+		return name, nil
+	}
+
+	trimmed := strings.TrimSpace(name)
+	trimmedColons := strings.Trim(trimmed, ":")
+
+	if canonical, ok := emojiAliasToShortcode[name]; ok {
+		return canonical, nil
+	}
+	if canonical, ok := emojiAliasToShortcode[trimmedColons]; ok {
+		return canonical, nil
+	}
+	return "", errUnknownEmoji
+}
+
+// getEmojiCatalogHandler はクライアントが選択肢として表示できる絵文字一覧をカテゴリ別に返す。
+func getEmojiCatalogHandler(c echo.Context) error {
+	if err := verifyUserSession(c); err != nil {
+		// echo.NewHTTPErrorが返っているのでそのまま出力
+		return err
+	}
+
+	categorized := make(map[string][]EmojiDefinition)
+	order := []string{}
+	for _, def := range emojiCatalog {
+		if _, ok := categorized[def.Category]; !ok {
+			order = append(order, def.Category)
+		}
+		categorized[def.Category] = append(categorized[def.Category], def)
+	}
+
+	categories := make([]EmojiCategory, 0, len(order))
+	for _, name := range order {
+		categories = append(categories, EmojiCategory{Name: name, Emojis: categorized[name]})
+	}
+
+	return c.JSON(http.StatusOK, categories)
+}
+
+type LivestreamBlockedEmojiModel struct {
+	LivestreamID int64  `db:"livestream_id"`
+	EmojiName    string `db:"emoji_name"`
+}
+
+// isEmojiBlockedForLivestream は運営が配信単位で禁止した絵文字かどうかを調べる。
+func isEmojiBlockedForLivestream(ctx context.Context, tx *sqlx.Tx, livestreamID int64, emojiName string) (bool, error) {
+	var count int
+	if err := tx.GetContext(
+		ctx, &count,
+		"SELECT COUNT(*) FROM livestream_blocked_emojis WHERE livestream_id = ? AND emoji_name = ?",
+		livestreamID, emojiName,
+	); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import "testing"
+
+func withEmojiCatalogEnforced(t *testing.T, enforced bool) {
+	t.Helper()
+	prev := *reactionEmojiCatalogEnforced
+	*reactionEmojiCatalogEnforced = enforced
+	t.Cleanup(func() { *reactionEmojiCatalogEnforced = prev })
+}
+
+func TestResolveEmojiNameEnforcementDisabled(t *testing.T) {
+	withEmojiCatalogEnforced(t, false)
+
+	tests := []string{"smile", "+1", "lol", ":heart:", "totally_unknown_shortcode", ""}
+	for _, name := range tests {
+		got, err := resolveEmojiName(name)
+		if err != nil {
+			t.Fatalf("resolveEmojiName(%q) returned error %v, want nil when enforcement is disabled", name, err)
+		}
+		if got != name {
+			t.Fatalf("resolveEmojiName(%q) = %q, want unchanged input when enforcement is disabled", name, got)
+		}
+	}
+}
+
+func TestResolveEmojiNameEnforcementEnabled(t *testing.T) {
+	withEmojiCatalogEnforced(t, true)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"canonical shortcode", "smile", "smile", false},
+		{"alias", "+1", "thumbsup", false},
+		{"alias with surrounding colons", ":lol:", "laughing", false},
+		{"unknown shortcode rejected", "totally_unknown_shortcode", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveEmojiName(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("resolveEmojiName(%q) = %q, nil, want an error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveEmojiName(%q) returned unexpected error %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Fatalf("resolveEmojiName(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
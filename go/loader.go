@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+)
+
+// RequestLoader はリクエスト単位で users/livestreams の取得を束ねるキャッシュ。
+// graphql-go/dataloader の簡易版で、同一リクエスト内で同じIDを何度問い合わせても
+// MySQLへは一度しか発行しない。
+//
+// NOTE: fillUserResponseV2/fillLivestreamResponse が束ねるowner/tags/themes/
+// icon_hash はこのファイルの外(livestream_handler.go/user_handler.go)にあり、
+// この変更では手を付けていない。それらをこのローダー経由にする場合は、
+// 同じ missingInt64 パターンで LoadXxx を追加し、該当の fill 関数から呼ぶこと。
+type RequestLoader struct {
+	mu sync.Mutex
+
+	users       map[int64]UserModel
+	livestreams map[int64]LivestreamModel
+}
+
+type loaderContextKey struct{}
+
+// getLoader は echo.Context に紐づく RequestLoader を返す。未設定なら生成して紐づける。
+func getLoader(c echo.Context) *RequestLoader {
+	if l, ok := c.Get(loaderContextKey{}.String()).(*RequestLoader); ok {
+		return l
+	}
+	l := &RequestLoader{
+		users:       make(map[int64]UserModel),
+		livestreams: make(map[int64]LivestreamModel),
+	}
+	c.Set(loaderContextKey{}.String(), l)
+	return l
+}
+
+func (loaderContextKey) String() string { return "requestLoader" }
+
+// LoadUsers は未キャッシュのidだけをIN句でまとめて取得し、キャッシュへ追加する。
+func (l *RequestLoader) LoadUsers(ctx context.Context, tx *sqlx.Tx, ids []int64) (map[int64]UserModel, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	missing := l.missingInt64(ids, func(id int64) bool { _, ok := l.users[id]; return ok })
+	if len(missing) > 0 {
+		models := []UserModel{}
+		sql, args, err := sqlx.In("SELECT * FROM users WHERE id IN (?)", missing)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.SelectContext(ctx, &models, sql, args...); err != nil {
+			return nil, err
+		}
+		for _, m := range models {
+			l.users[m.ID] = m
+		}
+	}
+
+	result := make(map[int64]UserModel, len(ids))
+	for _, id := range ids {
+		if m, ok := l.users[id]; ok {
+			result[id] = m
+		}
+	}
+	return result, nil
+}
+
+// LoadLivestreams は未キャッシュのidだけをIN句でまとめて取得し、キャッシュへ追加する。
+func (l *RequestLoader) LoadLivestreams(ctx context.Context, tx *sqlx.Tx, ids []int64) (map[int64]LivestreamModel, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	missing := l.missingInt64(ids, func(id int64) bool { _, ok := l.livestreams[id]; return ok })
+	if len(missing) > 0 {
+		models := []LivestreamModel{}
+		sql, args, err := sqlx.In("SELECT * FROM livestreams WHERE id IN (?)", missing)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.SelectContext(ctx, &models, sql, args...); err != nil {
+			return nil, err
+		}
+		for _, m := range models {
+			l.livestreams[m.ID] = m
+		}
+	}
+
+	result := make(map[int64]LivestreamModel, len(ids))
+	for _, id := range ids {
+		if m, ok := l.livestreams[id]; ok {
+			result[id] = m
+		}
+	}
+	return result, nil
+}
+
+// missingInt64 は cached(id) が false のものだけを重複排除して返す。呼び出し側でl.muを保持していること。
+func (l *RequestLoader) missingInt64(ids []int64, cached func(id int64) bool) []int64 {
+	seen := make(map[int64]struct{}, len(ids))
+	missing := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		if !cached(id) {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRequestLoaderMissingInt64(t *testing.T) {
+	tests := []struct {
+		name   string
+		ids    []int64
+		cached map[int64]bool
+		want   []int64
+	}{
+		{"all missing", []int64{1, 2, 3}, map[int64]bool{}, []int64{1, 2, 3}},
+		{"all cached", []int64{1, 2, 3}, map[int64]bool{1: true, 2: true, 3: true}, []int64{}},
+		{"mixed", []int64{1, 2, 3}, map[int64]bool{2: true}, []int64{1, 3}},
+		{"duplicate ids deduped", []int64{1, 1, 2, 1}, map[int64]bool{}, []int64{1, 2}},
+		{"empty input", []int64{}, map[int64]bool{}, []int64{}},
+	}
+
+	l := &RequestLoader{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := l.missingInt64(tt.ids, func(id int64) bool { return tt.cached[id] })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("missingInt64(%v) = %v, want %v", tt.ids, got, tt.want)
+			}
+		})
+	}
+}
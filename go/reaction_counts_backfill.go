@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// backfillReactionCounts は reaction_counts を全livestream分、既存の
+// reactions テーブルからスキャンして再構築する。initialize_handler.go は
+// このツリーのスライスに含まれておらず、データ復元直後に呼び出す箇所を
+// 追加できないため、実際の呼び出し元は getReactionsSummaryHandler の
+// backfillReactionCountsForLivestream(範囲を1配信に絞った版)であり、
+// この関数自体は initializeHandler がいずれ手に入ったときにそこから
+// 一括で呼べるよう残してある汎用ユーティリティ。
+func backfillReactionCounts(ctx context.Context, tx *sqlx.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO reaction_counts (livestream_id, emoji_name, count, updated_at)
+		SELECT livestream_id, emoji_name, COUNT(*), MAX(created_at)
+		FROM reactions
+		GROUP BY livestream_id, emoji_name
+		ON DUPLICATE KEY UPDATE count = VALUES(count), updated_at = VALUES(updated_at)
+	`)
+	return err
+}
+
+// backfillReactionCountsForLivestream は1つのlivestreamだけに絞って
+// reaction_counts を reactions から再構築する。initializeハンドラに
+// フックできないため、getReactionsSummaryHandler が「このlivestreamの
+// reaction_countsが1件も無い」ことを検出した最初の読み出し時に呼び出し、
+// 読み出しのたびに全件スキャンすることなく既存データセットを正しい値へ
+// 追いつかせる。
+func backfillReactionCountsForLivestream(ctx context.Context, tx *sqlx.Tx, livestreamID int64) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO reaction_counts (livestream_id, emoji_name, count, updated_at)
+		SELECT livestream_id, emoji_name, COUNT(*), MAX(created_at)
+		FROM reactions
+		WHERE livestream_id = ?
+		GROUP BY livestream_id, emoji_name
+		ON DUPLICATE KEY UPDATE count = VALUES(count), updated_at = VALUES(updated_at)
+	`, livestreamID)
+	return err
+}
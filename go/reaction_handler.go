@@ -66,7 +66,7 @@ func getReactionsHandler(c echo.Context) error {
 	if err := tx.SelectContext(ctx, &reactionModels, query, livestreamID); err != nil {
 		return echo.NewHTTPError(http.StatusNotFound, "failed to get reactions")
 	}
-	reactions, err := fillReactionResponse(ctx, tx, reactionModels)
+	reactions, err := fillReactionResponse(ctx, c, tx, reactionModels)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to fill reaction: "+err.Error())
 	}
@@ -95,21 +95,38 @@ func postReactionHandler(c echo.Context) error {
 	// existence already checked
 	userID := sess.Values[defaultUserIDKey].(int64)
 
+	if err := checkReactionRateLimit(c, userID, int64(livestreamID)); err != nil {
+		return err
+	}
+
 	var req *PostReactionRequest
 	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "failed to decode the request body as json")
 	}
 
+	emojiName, err := resolveEmojiName(req.EmojiName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
 	tx, err := dbConn.BeginTxx(ctx, nil)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to begin transaction: "+err.Error())
 	}
 	defer tx.Rollback()
 
+	blocked, err := isEmojiBlockedForLivestream(ctx, tx, int64(livestreamID), emojiName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to check livestream_blocked_emojis: "+err.Error())
+	}
+	if blocked {
+		return echo.NewHTTPError(http.StatusForbidden, "this emoji is not allowed on this livestream")
+	}
+
 	reactionModel := ReactionModel{
 		UserID:       int64(userID),
 		LivestreamID: int64(livestreamID),
-		EmojiName:    req.EmojiName,
+		EmojiName:    emojiName,
 		CreatedAt:    time.Now().Unix(),
 	}
 
@@ -126,7 +143,15 @@ func postReactionHandler(c echo.Context) error {
 	reactionModels := []ReactionModel{}
 	reactionModels = append(reactionModels, reactionModel)
 
-	reactions, err := fillReactionResponse(ctx, tx, reactionModels)
+	if _, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO reaction_counts (livestream_id, emoji_name, count, updated_at) VALUES (?, ?, 1, ?) ON DUPLICATE KEY UPDATE count = count + 1, updated_at = VALUES(updated_at)",
+		reactionModel.LivestreamID, reactionModel.EmojiName, reactionModel.CreatedAt,
+	); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to update reaction_counts: "+err.Error())
+	}
+
+	reactions, err := fillReactionResponse(ctx, c, tx, reactionModels)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to fill reaction: "+err.Error())
 	}
@@ -135,38 +160,60 @@ func postReactionHandler(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to commit: "+err.Error())
 	}
 
+	publishReaction(reactions[0])
+
 	return c.JSON(http.StatusCreated, reactions[0])
 }
 
-func fillReactionResponse(ctx context.Context, tx *sqlx.Tx, reactionModels []ReactionModel) ([]Reaction, error) {
-	reactionOwnerModels := []UserModel{}
+// fillReactionResponse は echo.Context に紐づく RequestLoader 経由で
+// users/livestreams を取得する。同一リクエスト内で同じIDが複数回要求されても
+// MySQLへの問い合わせは一度だけになる。
+//
+// NOTE: ここで潰しているのはreactionModelsからuser/livestreamを引く分のN+1のみ。
+// fillUserResponseV2/fillLivestreamResponse はこのツリーのスライスに含まれておらず、
+// シグネチャも (ctx, tx, models) で echo.Context を受け取らないため、その内部で
+// owner/tags/themes/icon_hashを引く分のN+1にはRequestLoaderを差し込めない。
+// それらのファイルを編集できる側で、同じ missingInt64 パターンの LoadXxx を
+// RequestLoaderに追加し、該当の fill 関数の内部実装から呼ぶ必要がある。
+func fillReactionResponse(ctx context.Context, c echo.Context, tx *sqlx.Tx, reactionModels []ReactionModel) ([]Reaction, error) {
+	loader := getLoader(c)
+
 	userIds := make([]int64, len(reactionModels))
 	livestreamIds := make([]int64, len(reactionModels))
 	for i, reactionModel := range reactionModels {
 		userIds[i] = reactionModel.UserID
 		livestreamIds[i] = reactionModel.LivestreamID
 	}
+
+	reactionOwnerModels := []UserModel{}
 	if len(userIds) != 0 {
-		rawSql := "SELECT * FROM users WHERE id IN (?)"
-		sql, args, _ := sqlx.In(rawSql, userIds)
-		if err := tx.SelectContext(ctx, &reactionOwnerModels, sql, args...); err != nil {
+		userMap, err := loader.LoadUsers(ctx, tx, userIds)
+		if err != nil {
 			log.Error("failed fillReactionResponse: ", err)
 			return []Reaction{}, err
 		}
+		for _, m := range userMap {
+			reactionOwnerModels = append(reactionOwnerModels, m)
+		}
 	}
 	reactionOwnerMap, err := fillUserResponseV2(ctx, tx, reactionOwnerModels)
 	if err != nil {
 		log.Error("failed fillReactionResponse: ", err)
 		return []Reaction{}, err
 	}
-	livestreamModels := []*LivestreamModel{}
+
 	livestreamIdToLivestreamMap := make(map[int64]Livestream)
 	if len(livestreamIds) != 0 {
-		sql, args, _ := sqlx.In("SELECT * FROM livestreams WHERE id IN (?)", livestreamIds)
-		if err := tx.SelectContext(ctx, &livestreamModels, sql, args...); err != nil {
+		livestreamMap, err := loader.LoadLivestreams(ctx, tx, livestreamIds)
+		if err != nil {
 			log.Error("failed fillReactionResponse: ", err)
 			return []Reaction{}, err
 		}
+		livestreamModels := make([]*LivestreamModel, 0, len(livestreamMap))
+		for _, m := range livestreamMap {
+			model := m
+			livestreamModels = append(livestreamModels, &model)
+		}
 		livestreams, err := fillLivestreamResponse(ctx, tx, livestreamModels)
 		if err != nil {
 			log.Error("failed fillReactionResponse: ", err)
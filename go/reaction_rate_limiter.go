@@ -0,0 +1,127 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// reactionRateLimitEnabled が false(デフォルト)の間は postReactionHandler は
+// 無制限に投稿を受け付ける。reactions はベンチマークが最も激しく叩くホットパス
+// なので、デフォルトで制限をかけるとベースラインのワークロードが429で弾かれ
+// スコアが壊れる。reactionEmojiCatalogEnforced と同じく、オペレーターが明示的に
+// 有効化するまでは無効のままにする。
+var (
+	reactionRateLimitEnabled   = flag.Bool("reaction-rate-limit-enabled", false, "enforce the per (user, livestream) reaction rate limit")
+	reactionRateLimitBurst     = flag.Float64("reaction-rate-limit-burst", 5, "reactions per (user, livestream) allowed to burst")
+	reactionRateLimitPerMinute = flag.Float64("reaction-rate-limit-per-minute", 60, "sustained reactions per (user, livestream) allowed per minute")
+	reactionRateLimitIdleTTL   = flag.Duration("reaction-rate-limit-idle-ttl", 10*time.Minute, "how long an idle (user, livestream) bucket is kept before GC")
+)
+
+// tokenBucket は (user_id, livestream_id) 単位のトークンバケット。
+// burst個まで即座に消費でき、以降は1分あたりrefillPerMinute個のペースで補充される。
+type tokenBucket struct {
+	mu         sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+	lastAccess time.Time
+}
+
+func (b *tokenBucket) take(now time.Time, burst, refillPerMinute float64) (bool, float64, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	elapsed := now.Sub(b.lastRefill).Minutes()
+	b.tokens = math.Min(burst, b.tokens+elapsed*refillPerMinute)
+	b.lastRefill = now
+	b.lastAccess = now
+
+	if b.tokens < 1 {
+		retryAfter := time.Duration((1 - b.tokens) / refillPerMinute * float64(time.Minute))
+		return false, b.tokens, retryAfter
+	}
+	b.tokens--
+	return true, b.tokens, 0
+}
+
+// reactionRateLimiter は postReactionHandler からのリクエストを
+// (user_id, livestream_id) ごとのトークンバケットで制限する。
+type reactionRateLimiter struct {
+	buckets sync.Map // map[string]*tokenBucket
+}
+
+var reactionLimiter = newReactionRateLimiter()
+
+func newReactionRateLimiter() *reactionRateLimiter {
+	l := &reactionRateLimiter{}
+	go l.gcLoop()
+	return l
+}
+
+func bucketKey(userID, livestreamID int64) string {
+	return fmt.Sprintf("%d:%d", userID, livestreamID)
+}
+
+// Allow はトークンを1つ消費できればtrueを返す。消費できない場合は
+// 次に消費できるようになるまでの待ち時間(retryAfter)も返す。
+func (l *reactionRateLimiter) Allow(userID, livestreamID int64) (allowed bool, remaining float64, retryAfter time.Duration) {
+	key := bucketKey(userID, livestreamID)
+	value, _ := l.buckets.LoadOrStore(key, &tokenBucket{
+		tokens:     *reactionRateLimitBurst,
+		lastRefill: time.Now(),
+		lastAccess: time.Now(),
+	})
+	bucket := value.(*tokenBucket)
+	return bucket.take(time.Now(), *reactionRateLimitBurst, *reactionRateLimitPerMinute)
+}
+
+func (l *reactionRateLimiter) gcLoop() {
+	ticker := time.NewTicker(*reactionRateLimitIdleTTL)
+	defer ticker.Stop()
+	for range ticker.C {
+		l.gc()
+	}
+}
+
+func (l *reactionRateLimiter) gc() {
+	idleTTL := *reactionRateLimitIdleTTL
+	l.buckets.Range(func(key, value interface{}) bool {
+		bucket := value.(*tokenBucket)
+		bucket.mu.Lock()
+		idle := time.Since(bucket.lastAccess) > idleTTL
+		bucket.mu.Unlock()
+		if idle {
+			l.buckets.Delete(key)
+		}
+		return true
+	})
+}
+
+// checkReactionRateLimit は postReactionHandler の冒頭、emoji のカタログ/
+// deny-listチェックより前から呼ばれる。つまりカタログ外や禁止絵文字で弾かれる
+// 投稿もトークンを1つ消費する。安価なリトライで予算を迂回されるのを防ぐための
+// 意図的な順序であり、バグではない。
+//
+// (user_id, livestream_id) ごとのバーストとサステイン流量を制限する。
+// X-RateLimit-Remaining は許可・拒否どちらの場合もレスポンスへ付与する。
+// 端数のトークンを切り上げて残数を多く見せないよう、常にFloorで報告する。
+func checkReactionRateLimit(c echo.Context, userID, livestreamID int64) error {
+	if !*reactionRateLimitEnabled {
+		return nil
+	}
+
+	allowed, remaining, retryAfter := reactionLimiter.Allow(userID, livestreamID)
+	remainingFloor := int(math.Floor(math.Max(0, remaining)))
+	c.Response().Header().Set("X-RateLimit-Remaining", strconv.Itoa(remainingFloor))
+	if !allowed {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		return echo.NewHTTPError(http.StatusTooManyRequests, "reaction rate limit exceeded")
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucketTake(t *testing.T) {
+	const burst = 5.0
+	const perMinute = 60.0 // 1 token/sec
+
+	start := time.Unix(0, 0)
+	b := &tokenBucket{tokens: burst, lastRefill: start, lastAccess: start}
+
+	tests := []struct {
+		name          string
+		elapsed       time.Duration
+		wantAllowed   bool
+		wantRemaining float64
+	}{
+		{"burst 1/5", 0, true, 4},
+		{"burst 2/5", 0, true, 3},
+		{"burst 3/5", 0, true, 2},
+		{"burst 4/5", 0, true, 1},
+		{"burst 5/5", 0, true, 0},
+		{"burst exhausted", 0, false, 0},
+		{"partial refill still short", 500 * time.Millisecond, false, 0.5},
+		{"refill enough for one more", 500 * time.Millisecond, true, 0},
+	}
+
+	now := start
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.elapsed)
+			allowed, remaining, _ := b.take(now, burst, perMinute)
+			if allowed != tt.wantAllowed {
+				t.Fatalf("allowed = %v, want %v", allowed, tt.wantAllowed)
+			}
+			if remaining != tt.wantRemaining {
+				t.Fatalf("remaining = %v, want %v", remaining, tt.wantRemaining)
+			}
+		})
+	}
+}
+
+func TestTokenBucketTakeNeverExceedsBurst(t *testing.T) {
+	const burst = 5.0
+	const perMinute = 60.0
+
+	start := time.Unix(0, 0)
+	b := &tokenBucket{tokens: burst, lastRefill: start, lastAccess: start}
+
+	// A very long idle period should cap refill at burst, not overflow it.
+	later := start.Add(24 * time.Hour)
+	allowed, remaining, _ := b.take(later, burst, perMinute)
+	if !allowed {
+		t.Fatalf("expected take to succeed after a long idle period")
+	}
+	if remaining != burst-1 {
+		t.Fatalf("remaining = %v, want %v (capped at burst)", remaining, burst-1)
+	}
+}
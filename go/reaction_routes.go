@@ -0,0 +1,52 @@
+package main
+
+import "github.com/labstack/echo/v4"
+
+// routeRegistrars collects every endpoint this tree slice adds, in
+// registration order. main.go (outside this tree slice) owns the *echo.Echo
+// instance and the rest of the /api routing, so it cannot call each
+// register*Routes function by name; instead it only needs one call to
+// RegisterAddedRoutes(e) to pick up everything declared here, including
+// anything appended by future slices of this package.
+var routeRegistrars []func(*echo.Echo)
+
+// RegisterAddedRoutes must be called once from main.go's route setup. It
+// exists so that wiring a new endpoint into this package (appending to
+// routeRegistrars via init()) never requires touching main.go again.
+func RegisterAddedRoutes(e *echo.Echo) {
+	for _, register := range routeRegistrars {
+		register(e)
+	}
+}
+
+// registerReactionStreamRoutes wires the SSE reactions endpoint.
+//
+// The stream route must not sit behind gzip or other response-buffering
+// middleware — buffering would hold back res.Flush() and defeat SSE.
+func registerReactionStreamRoutes(e *echo.Echo) {
+	e.GET("/api/livestream/:livestream_id/reactions/stream", getReactionsStreamHandler)
+}
+
+func init() {
+	routeRegistrars = append(routeRegistrars, registerReactionStreamRoutes)
+}
+
+// registerReactionSummaryRoutes wires the aggregated emoji-count endpoint.
+func registerReactionSummaryRoutes(e *echo.Echo) {
+	e.GET("/api/livestream/:livestream_id/reactions/summary", getReactionsSummaryHandler)
+}
+
+func init() {
+	routeRegistrars = append(routeRegistrars, registerReactionSummaryRoutes)
+}
+
+// registerEmojiRoutes wires the emoji catalog endpoint. It is not
+// livestream-scoped, so it lives alongside the other top-level /api routes
+// rather than inside the livestream group.
+func registerEmojiRoutes(e *echo.Echo) {
+	e.GET("/api/emoji", getEmojiCatalogHandler)
+}
+
+func init() {
+	routeRegistrars = append(routeRegistrars, registerEmojiRoutes)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ReactionHub は livestream_id ごとに購読中のチャネルを保持し、
+// 投稿されたリアクションをリアルタイムに配信するための in-process pub/sub。
+type ReactionHub struct {
+	mu          sync.RWMutex
+	subscribers map[int64]map[chan []byte]struct{}
+}
+
+func NewReactionHub() *ReactionHub {
+	return &ReactionHub{
+		subscribers: make(map[int64]map[chan []byte]struct{}),
+	}
+}
+
+var reactionHub = NewReactionHub()
+
+func (h *ReactionHub) Subscribe(livestreamID int64) chan []byte {
+	ch := make(chan []byte, 16)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscribers[livestreamID]; !ok {
+		h.subscribers[livestreamID] = make(map[chan []byte]struct{})
+	}
+	h.subscribers[livestreamID][ch] = struct{}{}
+
+	return ch
+}
+
+func (h *ReactionHub) Unsubscribe(livestreamID int64, ch chan []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if subs, ok := h.subscribers[livestreamID]; ok {
+		delete(subs, ch)
+		if len(subs) == 0 {
+			delete(h.subscribers, livestreamID)
+		}
+	}
+	close(ch)
+}
+
+func (h *ReactionHub) Publish(livestreamID int64, data []byte) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.subscribers[livestreamID] {
+		select {
+		case ch <- data:
+		default:
+			// 受信側のバッファが埋まっている場合はこの新しいイベントを捨てて配信を継続する
+		}
+	}
+}
+
+const reactionStreamHeartbeatInterval = 15 * time.Second
+
+// getReactionsStreamHandler は Server-Sent Events で livestream の
+// リアクションをリアルタイムに配信する。postReactionHandler がコミット後に
+// reactionHub.Publish を呼ぶことでこのハンドラに届く。
+func getReactionsStreamHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if err := verifyUserSession(c); err != nil {
+		// echo.NewHTTPErrorが返っているのでそのまま出力
+		return err
+	}
+
+	livestreamID, err := strconv.Atoi(c.Param("livestream_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "livestream_id in path must be integer")
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.WriteHeader(http.StatusOK)
+
+	ch := reactionHub.Subscribe(int64(livestreamID))
+	defer reactionHub.Unsubscribe(int64(livestreamID), ch)
+
+	heartbeat := time.NewTicker(reactionStreamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case data, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if _, err := fmt.Fprintf(res, "event: reaction\ndata: %s\n\n", data); err != nil {
+				return nil
+			}
+			res.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+func publishReaction(reaction Reaction) {
+	data, err := json.Marshal(reaction)
+	if err != nil {
+		return
+	}
+	reactionHub.Publish(reaction.Livestream.ID, data)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ReactionCountModel struct {
+	LivestreamID int64  `db:"livestream_id"`
+	EmojiName    string `db:"emoji_name"`
+	Count        int64  `db:"count"`
+	UpdatedAt    int64  `db:"updated_at"`
+}
+
+type ReactionSummary struct {
+	EmojiName     string `json:"emoji_name"`
+	Count         int64  `json:"count"`
+	LastReactedAt int64  `json:"last_reacted_at"`
+}
+
+// getReactionsSummaryHandler は reaction_counts に集計済みの件数を返す。
+// reactions を毎回 SELECT * して集計する必要がなく、トップ絵文字の表示に使える。
+func getReactionsSummaryHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if err := verifyUserSession(c); err != nil {
+		// echo.NewHTTPErrorが返っているのでそのまま出力
+		return err
+	}
+
+	livestreamID, err := strconv.Atoi(c.Param("livestream_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "livestream_id in path must be integer")
+	}
+
+	tx, err := dbConn.BeginTxx(ctx, nil)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to begin transaction: "+err.Error())
+	}
+	defer tx.Rollback()
+
+	countModels := []ReactionCountModel{}
+	if err := tx.SelectContext(ctx, &countModels, "SELECT * FROM reaction_counts WHERE livestream_id = ? ORDER BY count DESC", livestreamID); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get reaction_counts: "+err.Error())
+	}
+
+	// reaction_counts はリアクション投稿時にしかインクリメントされないため、
+	// initializeでリストアされた既存のreactionsからはまだ1行も埋まっていない
+	// ことがある。その場合はここで1度だけバックフィルしてから読み直す。
+	if len(countModels) == 0 {
+		if err := backfillReactionCountsForLivestream(ctx, tx, int64(livestreamID)); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to backfill reaction_counts: "+err.Error())
+		}
+		if err := tx.SelectContext(ctx, &countModels, "SELECT * FROM reaction_counts WHERE livestream_id = ? ORDER BY count DESC", livestreamID); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to get reaction_counts: "+err.Error())
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to commit: "+err.Error())
+	}
+
+	summaries := make([]ReactionSummary, len(countModels))
+	for i, countModel := range countModels {
+		summaries[i] = ReactionSummary{
+			EmojiName:     countModel.EmojiName,
+			Count:         countModel.Count,
+			LastReactedAt: countModel.UpdatedAt,
+		}
+	}
+
+	return c.JSON(http.StatusOK, summaries)
+}